@@ -0,0 +1,380 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/rwxrob/bonzai/json"
+)
+
+// EncodeTo writes n in the same two-element array form MarshalJSON
+// produces, but streams it directly to w instead of building the
+// whole document as a string first. MarshalJSON's buf += approach is
+// O(n²) and holds the entire tree in memory at once; EncodeTo writes
+// each token as it is produced and never holds more than the current
+// path of open brackets.
+func (n *Node) EncodeTo(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if err := n.encodeTo(bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func (n *Node) encodeTo(w *bufio.Writer) error {
+	list := n.AllUnder()
+	if len(list) == 0 {
+		return n.shallowEncode(w)
+	}
+	if err := w.WriteByte('['); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%d,[", n.T); err != nil {
+		return err
+	}
+	for i, c := range list {
+		if i > 0 {
+			if err := w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		if err := c.encodeTo(w); err != nil {
+			return err
+		}
+	}
+	_, err := w.WriteString("]]")
+	return err
+}
+
+// shallowEncode writes n's own [T] or [T,"V"] form without descending
+// into its children.
+func (n *Node) shallowEncode(w *bufio.Writer) error {
+	if n.V == "" {
+		if n.T == 0 {
+			_, err := w.WriteString("[]")
+			return err
+		}
+		_, err := fmt.Fprintf(w, "[%d]", n.T)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "[%d,\"%v\"]", n.T, json.Escape(n.V))
+	return err
+}
+
+// DecodeFrom reads the two-element array form EncodeTo and
+// MarshalJSON produce from r using a hand-rolled tokenizer (no
+// encoding/json reflection) and replaces n's contents with it via
+// Morph, the same way UnmarshalJSON does.
+func (n *Node) DecodeFrom(r io.Reader) error {
+	br := toByteReader(r)
+	c, err := decodeNode(br, n.tree)
+	if err != nil {
+		return err
+	}
+	return n.Morph(c)
+}
+
+func toByteReader(r io.Reader) *bufio.Reader {
+	if br, ok := r.(*bufio.Reader); ok {
+		return br
+	}
+	return bufio.NewReader(r)
+}
+
+func decodeNode(br *bufio.Reader, tr *Tree) (*Node, error) {
+	if err := expect(br, '['); err != nil {
+		return nil, err
+	}
+	n := &Node{tree: tr}
+
+	b, err := br.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if b == ']' {
+		return n, nil // [] unknown type, nothing under
+	}
+	if err := br.UnreadByte(); err != nil {
+		return nil, err
+	}
+
+	t, err := readInt(br)
+	if err != nil {
+		return nil, err
+	}
+	n.T = t
+
+	b, err = br.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if b == ']' {
+		return n, nil // [T] branch with nothing under (yet)
+	}
+	if b != ',' {
+		return nil, fmt.Errorf("tree: expected ',' got %q", b)
+	}
+
+	b, err = br.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch b {
+
+	case '"':
+		v, err := readString(br)
+		if err != nil {
+			return nil, err
+		}
+		n.V = v
+		return n, expect(br, ']')
+
+	case '[':
+		// b was the children list's own opening '[', already
+		// consumed; each child below consumes its own.
+		for {
+			c, err := decodeNode(br, tr)
+			if err != nil {
+				return nil, err
+			}
+			n.GraftUnder(c)
+			b, err := br.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			if b == ']' {
+				break
+			}
+			if b != ',' {
+				return nil, fmt.Errorf("tree: expected ',' got %q", b)
+			}
+		}
+		return n, expect(br, ']')
+
+	default:
+		return nil, fmt.Errorf("tree: expected '\"' or '[' got %q", b)
+	}
+}
+
+func expect(br *bufio.Reader, want byte) error {
+	b, err := br.ReadByte()
+	if err != nil {
+		return err
+	}
+	if b != want {
+		return fmt.Errorf("tree: expected %q got %q", want, b)
+	}
+	return nil
+}
+
+func readInt(br *bufio.Reader) (int, error) {
+	var digits []byte
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b < '0' || b > '9' {
+			if err := br.UnreadByte(); err != nil {
+				return 0, err
+			}
+			break
+		}
+		digits = append(digits, b)
+	}
+	if len(digits) == 0 {
+		return 0, fmt.Errorf("tree: expected integer")
+	}
+	return strconv.Atoi(string(digits))
+}
+
+// readString reads a quoted string whose opening quote has already
+// been consumed, reassembles the original quoted bytes, and lets
+// strconv.Unquote do the escape handling so every standard escape
+// (including \uXXXX) is honored without reimplementing it by hand.
+func readString(br *bufio.Reader) (string, error) {
+	raw := []byte{'"'}
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		raw = append(raw, b)
+		if b == '\\' {
+			b2, err := br.ReadByte()
+			if err != nil {
+				return "", err
+			}
+			raw = append(raw, b2)
+			continue
+		}
+		if b == '"' {
+			break
+		}
+	}
+	return strconv.Unquote(string(raw))
+}
+
+// ------------------------------- JSONL -------------------------------
+
+// EncodeJSONL writes one line per Node under n (n included), each
+// holding the Node's own shallow [T]/[T,"V"] form alongside the type
+// path of its ancestors from the root down, e.g. [[1,3,2],[7,"foo"]].
+// This lets a huge tree be sharded across multiple files or streams
+// and reassembled later with DecodeJSONL.
+func (n *Node) EncodeJSONL(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if err := writeJSONLNode(bw, n, nil); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func writeJSONLNode(w *bufio.Writer, n *Node, path []int) error {
+	if err := w.WriteByte('['); err != nil {
+		return err
+	}
+	if err := writeIntSlice(w, path); err != nil {
+		return err
+	}
+	if err := w.WriteByte(','); err != nil {
+		return err
+	}
+	if err := n.shallowEncode(w); err != nil {
+		return err
+	}
+	if _, err := w.WriteString("]\n"); err != nil {
+		return err
+	}
+
+	children := n.AllUnder()
+	if len(children) == 0 {
+		return nil
+	}
+	childPath := append(append([]int(nil), path...), n.T)
+	for _, c := range children {
+		if err := writeJSONLNode(w, c, childPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeIntSlice(w *bufio.Writer, is []int) error {
+	if err := w.WriteByte('['); err != nil {
+		return err
+	}
+	for i, v := range is {
+		if i > 0 {
+			if err := w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%d", v); err != nil {
+			return err
+		}
+	}
+	return w.WriteByte(']')
+}
+
+// DecodeJSONL reassembles a tree written by EncodeJSONL. The type
+// path on each line identifies which shard a line belongs to when
+// sharded across files; reassembly itself relies on lines arriving in
+// the same top-to-bottom, left-to-right preorder EncodeJSONL writes
+// them in, using each line's path length as its depth to find its
+// parent among the most recently decoded node at the depth above it.
+func DecodeJSONL(r io.Reader, types map[int]string, typesm map[string]int) (*Node, error) {
+	tr := &Tree{types: types, typesm: typesm}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+
+	var root *Node
+	var stack []*Node
+
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		br := bufio.NewReader(bytes.NewReader(line))
+
+		if err := expect(br, '['); err != nil {
+			return nil, err
+		}
+		if err := expect(br, '['); err != nil {
+			return nil, err
+		}
+		depth, err := readIntSliceLen(br)
+		if err != nil {
+			return nil, err
+		}
+		if err := expect(br, ','); err != nil {
+			return nil, err
+		}
+		n, err := decodeNode(br, tr)
+		if err != nil {
+			return nil, err
+		}
+		if err := expect(br, ']'); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case depth == 0:
+			root = n
+		case depth <= len(stack):
+			stack[depth-1].GraftUnder(n)
+		default:
+			return nil, fmt.Errorf("tree: jsonl line out of order at depth %d", depth)
+		}
+
+		switch {
+		case depth < len(stack):
+			stack[depth] = n
+			stack = stack[:depth+1]
+		case depth == len(stack):
+			stack = append(stack, n)
+		}
+	}
+	return root, sc.Err()
+}
+
+// readIntSliceLen reads a "[...]" list of ints, whose opening bracket
+// has already been consumed, and returns only how many there were;
+// the path's values only matter for shard identification, not for
+// reassembly (see DecodeJSONL).
+func readIntSliceLen(br *bufio.Reader) (int, error) {
+	b, err := br.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if b == ']' {
+		return 0, nil
+	}
+	if err := br.UnreadByte(); err != nil {
+		return 0, err
+	}
+	n := 0
+	for {
+		if _, err := readInt(br); err != nil {
+			return 0, err
+		}
+		n++
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b == ']' {
+			return n, nil
+		}
+		if b != ',' {
+			return 0, fmt.Errorf("tree: malformed path, expected ',' got %q", b)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import (
+	"io"
+	"testing"
+)
+
+// buildBenchTree returns a tree with n leaves under a single root,
+// large enough to make MarshalJSON's O(n²) string concatenation show
+// up against EncodeTo's streaming writes.
+func buildBenchTree(n int) *Node {
+	tr := &Tree{types: map[int]string{1: "Root", 2: "Leaf"}}
+	root := &Node{T: 1, tree: tr}
+	for i := 0; i < n; i++ {
+		root.GraftUnder(&Node{T: 2, V: "leaf", tree: tr})
+	}
+	return root
+}
+
+func BenchmarkMarshalJSON_100k(b *testing.B) {
+	root := buildBenchTree(100_000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := root.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeTo_100k(b *testing.B) {
+	root := buildBenchTree(100_000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := root.EncodeTo(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import (
+	"bytes"
+	"fmt"
+)
+
+func ExampleNode_EncodeTo() {
+	tr := &Tree{types: map[int]string{1: "Root", 2: "Leaf"}}
+	root := &Node{T: 1, tree: tr}
+	root.GraftUnder(&Node{T: 2, V: "a", tree: tr})
+	root.GraftUnder(&Node{T: 2, V: "b", tree: tr})
+
+	var buf bytes.Buffer
+	if err := root.EncodeTo(&buf); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	out := &Node{tree: tr}
+	if err := out.DecodeFrom(bytes.NewReader(buf.Bytes())); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(out.JSON() == root.JSON())
+	fmt.Println(out.JSON())
+	// Output:
+	// true
+	// [1,[[2,"a"],[2,"b"]]]
+}
+
+func ExampleNode_EncodeJSONL() {
+	tr := &Tree{types: map[int]string{1: "Root", 2: "Leaf"}}
+	root := &Node{T: 1, tree: tr}
+	root.GraftUnder(&Node{T: 2, V: "a", tree: tr})
+	root.GraftUnder(&Node{T: 2, V: "b", tree: tr})
+
+	var buf bytes.Buffer
+	if err := root.EncodeJSONL(&buf); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	out, err := DecodeJSONL(bytes.NewReader(buf.Bytes()), tr.types, tr.typesm)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(out.JSON() == root.JSON())
+	// Output:
+	// true
+}
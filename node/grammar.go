@@ -0,0 +1,173 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/bonzai/is"
+)
+
+// Cap captures a successful match of Expr as a typed Node. It takes
+// the same place in a Grammar's expression tree that is.Seq, is.In,
+// is.Not, is.Lk, and is.Opt take in a bonzai scan expression, but
+// unlike those it produces output: every Cap becomes one Node, with
+// Type giving the Node's T and Expr giving the sub-expression that
+// must match for the capture to succeed.
+type Cap struct {
+	Type int
+	Expr any
+}
+
+// Grammar wraps a rooted expression tree built from bonzai is
+// combinators (is.Seq, is.In, is.Not, is.Lk, is.Opt, is.Rng) and Cap,
+// and compiles it into a Parse function that scans an input buffer
+// once, producing a typed tree.Node for every Cap that matches. This
+// gives callers a single-shot way to go from grammar to typed tree
+// without hand-writing recursive descent, complementing UnmarshalJSON
+// and Parse. Every Node Parse returns carries the same tree as
+// decodeNode stamps onto Parse's output, so Get, First, Exists, and
+// Iterator (including SeekPath) all work against it right away.
+type Grammar struct {
+	Root any
+	tree *Tree
+}
+
+// NewGrammar wraps root, which must contain at least one Cap so
+// Parse has something to build a tree from, and types, used the same
+// way Parse uses them: to resolve the Cap Types captured Nodes carry
+// into names for Get and friends.
+func NewGrammar(root any, types map[int]string) *Grammar {
+	return &Grammar{Root: root, tree: newTree(types)}
+}
+
+// Parse runs the grammar against in from byte 0 and returns the Node
+// captured by the outermost Cap reached in Root. It is an error if in
+// is not fully consumed or if Root fails to match.
+func (g *Grammar) Parse(in []byte) (*Node, error) {
+	s := &scanner{buf: in, tree: g.tree}
+	caps, ok := s.match(g.Root)
+	if !ok {
+		return nil, fmt.Errorf("tree: grammar did not match at byte %d", s.pos)
+	}
+	if len(caps) == 0 {
+		return nil, fmt.Errorf("tree: grammar matched but captured nothing; wrap Root in a Cap")
+	}
+	if s.pos != len(in) {
+		return nil, fmt.Errorf("tree: grammar left %d of %d bytes unconsumed", len(in)-s.pos, len(in))
+	}
+	return caps[0], nil
+}
+
+// scanner is a byte cursor with mark/rewind so combinators that
+// backtrack (is.In, is.Opt, is.Not, is.Lk) can try an alternative
+// without disturbing bytes a failed sibling already consumed.
+type scanner struct {
+	buf  []byte
+	pos  int
+	tree *Tree
+}
+
+func (s *scanner) mark() int       { return s.pos }
+func (s *scanner) rewind(mark int) { s.pos = mark }
+
+// match evaluates expr at the scanner's current position and returns
+// every Node captured directly by expr (not by its descendants, which
+// are already grafted under them) along with whether expr matched.
+func (s *scanner) match(expr any) ([]*Node, bool) {
+	switch e := expr.(type) {
+
+	case string:
+		if len(s.buf)-s.pos < len(e) || string(s.buf[s.pos:s.pos+len(e)]) != e {
+			return nil, false
+		}
+		s.pos += len(e)
+		return nil, true
+
+	case byte:
+		if s.pos >= len(s.buf) || s.buf[s.pos] != e {
+			return nil, false
+		}
+		s.pos++
+		return nil, true
+
+	case is.Rng:
+		if s.pos >= len(s.buf) {
+			return nil, false
+		}
+		if c := s.buf[s.pos]; c < e.Lo || c > e.Hi {
+			return nil, false
+		}
+		s.pos++
+		return nil, true
+
+	case is.Seq:
+		start := s.mark()
+		var caps []*Node
+		for _, sub := range e {
+			c, ok := s.match(sub)
+			if !ok {
+				s.rewind(start)
+				return nil, false
+			}
+			caps = append(caps, c...)
+		}
+		return caps, true
+
+	case is.In:
+		for _, sub := range e {
+			start := s.mark()
+			if c, ok := s.match(sub); ok {
+				return c, true
+			}
+			s.rewind(start)
+		}
+		return nil, false
+
+	case is.Not:
+		start := s.mark()
+		_, ok := s.match(e.Expr)
+		s.rewind(start)
+		return nil, !ok
+
+	case is.Lk:
+		// Lk only peeks: nothing it matches was actually consumed, so
+		// any Cap inside it is discarded rather than recorded, or
+		// a later sibling that re-consumes the same bytes would
+		// produce a duplicate Node for them.
+		start := s.mark()
+		_, ok := s.match(e.Expr)
+		s.rewind(start)
+		return nil, ok
+
+	case is.Opt:
+		start := s.mark()
+		c, ok := s.match(e.Expr)
+		if !ok {
+			s.rewind(start)
+			return nil, true
+		}
+		return c, true
+
+	case Cap:
+		start := s.mark()
+		kids, ok := s.match(e.Expr)
+		if !ok {
+			s.rewind(start)
+			return nil, false
+		}
+		n := &Node{T: e.Type, tree: s.tree}
+		if len(kids) == 0 {
+			n.V = string(s.buf[start:s.pos])
+		} else {
+			for _, k := range kids {
+				n.GraftUnder(k)
+			}
+		}
+		return []*Node{n}, true
+
+	default:
+		panic(fmt.Sprintf("tree: unsupported grammar expression %T", expr))
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package tree_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/bonzai/is"
+	tree "github.com/rwxrob/structs/node"
+)
+
+const (
+	typeWord = 2
+	typePair = 3
+)
+
+var grammarTypes = map[int]string{typeWord: "Word", typePair: "Pair"}
+
+// ExampleGrammar_Parse builds a tiny grammar for "word,word" where a
+// word is one or two digits, and checks that each word is captured as
+// its own Node under the pair.
+func ExampleGrammar_Parse() {
+	digit := is.Rng{Lo: '0', Hi: '9'}
+	word := tree.Cap{Type: typeWord, Expr: is.In{is.Seq{digit, digit}, digit}}
+	pair := tree.Cap{Type: typePair, Expr: is.Seq{word, ",", word}}
+
+	n, err := tree.NewGrammar(pair, grammarTypes).Parse([]byte("1,23"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(n.JSON())
+	// Output:
+	// [3,[[2,"1"],[2,"23"]]]
+}
+
+// ExampleGrammar_Parse_lookaheadDoesNotCapture confirms that a Cap
+// reached only through is.Lk never shows up in the result tree, since
+// Lk never consumes what it matches.
+func ExampleGrammar_Parse_lookaheadDoesNotCapture() {
+	digit := is.Rng{Lo: '0', Hi: '9'}
+	peek := tree.Cap{Type: typeWord, Expr: digit}
+	root := tree.Cap{Type: typePair, Expr: is.Seq{is.Lk{Expr: peek}, digit}}
+
+	n, err := tree.NewGrammar(root, grammarTypes).Parse([]byte("5"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(n.JSON())
+	// Output:
+	// [3,"5"]
+}
+
+// ExampleGrammar_Parse_get confirms that Nodes Grammar.Parse returns
+// carry the Tree needed for Get, First, Exists, and Iterator's
+// SeekPath to work, the same way Parse's output does.
+func ExampleGrammar_Parse_get() {
+	digit := is.Rng{Lo: '0', Hi: '9'}
+	word := tree.Cap{Type: typeWord, Expr: is.In{is.Seq{digit, digit}, digit}}
+	pair := tree.Cap{Type: typePair, Expr: is.Seq{word, ",", word}}
+
+	n, err := tree.NewGrammar(pair, grammarTypes).Parse([]byte("1,23"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, w := range n.Get("Word") {
+		fmt.Println(w.V)
+	}
+
+	it := n.Iterator().SeekPath("Word")
+	first, ok := it.Next()
+	fmt.Println(ok, first.V)
+	// Output:
+	// 1
+	// 23
+	// true 1
+}
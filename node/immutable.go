@@ -0,0 +1,241 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import "fmt"
+
+// INode is the persistent, copy-on-write counterpart to Node. Readers
+// can hold a *INode returned from Snapshot (or from a prior Commit)
+// indefinitely: nothing about it will ever change underneath them.
+// Writers describe edits through a Txn, which clones only the node
+// being changed and every ancestor back up to the root, reusing every
+// untouched subtree by sharing its pointer (the path-copying technique
+// used by hashicorp's immutable radix tree).
+//
+// Node keeps the tree linked with left/right/up pointers so that any
+// Node can find its branch and peers without help. That shape does not
+// suit path-copying: re-linking a whole spine of up/left/right
+// pointers on every edit would mean touching nodes that did not
+// actually change. INode instead keeps an ordered slice of children
+// and no up pointer at all. A node's ancestry is never stored on it;
+// it only exists implicitly as the path taken to reach it from
+// whichever root is being read, and is reconstructed lazily by
+// whoever is walking the tree (see Iterator, added separately) rather
+// than kept live on the node.
+type INode struct {
+	T        int
+	V        string
+	children []*INode
+}
+
+// NewINode builds a detached INode with the given type, value, and
+// children. Passing any children makes V ineffective, matching Node's
+// branch-or-leaf rule.
+func NewINode(t int, v string, children ...*INode) *INode {
+	return &INode{T: t, V: v, children: children}
+}
+
+// ChildrenUnder returns the children of n in order, or nil if n is
+// a leaf.
+func (n *INode) ChildrenUnder() []*INode { return n.children }
+
+// IsLeaf returns true if n has no children but does have a value.
+func (n *INode) IsLeaf() bool { return len(n.children) == 0 && n.V != "" }
+
+// IsBranch returns true if n has any children at all.
+func (n *INode) IsBranch() bool { return len(n.children) > 0 }
+
+// MarshalJSON fulfills the interface with the same two-element array
+// form Node uses ([T,"V"] for leaves, [T,[...]] for branches) so
+// encoding an INode and a Node produce identical output.
+func (n *INode) MarshalJSON() ([]byte, error) {
+	if len(n.children) == 0 {
+		if n.V == "" {
+			if n.T == 0 {
+				return []byte("[]"), nil
+			}
+			return []byte(fmt.Sprintf(`[%d]`, n.T)), nil
+		}
+		return []byte(fmt.Sprintf(`[%d,%q]`, n.T, n.V)), nil
+	}
+	buf := "["
+	for i, c := range n.children {
+		byt, _ := c.MarshalJSON() // no error ever returned
+		if i > 0 {
+			buf += ","
+		}
+		buf += string(byt)
+	}
+	buf += "]"
+	return []byte(fmt.Sprintf(`[%d,%v]`, n.T, buf)), nil
+}
+
+// ITree is the persistent counterpart to Tree: a stable handle that
+// readers resolve against a root which never mutates, while writers
+// publish new roots through Txn.Commit. types and typesm are kept for
+// the same reason Tree keeps them, to resolve type names without
+// requiring every caller to carry them around.
+type ITree struct {
+	root   *INode
+	types  map[int]string
+	typesm map[string]int
+}
+
+// NewITree creates an ITree using the given type tables, which are
+// shared read-only with every Snapshot and Txn taken from it. Its
+// root is seeded with type 1, the same rule Tree uses for a new
+// Tree's Root.
+func NewITree(types map[int]string, typesm map[string]int) *ITree {
+	return &ITree{root: &INode{T: 1}, types: types, typesm: typesm}
+}
+
+// Snapshot returns the current root. Because INode is immutable, the
+// returned root is guaranteed to still reflect this exact state no
+// matter how many further Txns are committed against the ITree.
+func (t *ITree) Snapshot() *INode { return t.root }
+
+// Txn begins a new transaction rooted at the ITree's current root.
+// Edits made through the Txn are invisible to the ITree (and to any
+// outstanding Snapshot) until Commit is called.
+func (t *ITree) Txn() *Txn { return &Txn{tree: t, root: t.root} }
+
+// Txn batches edits against a persistent tree and publishes them
+// atomically. Edits are addressed by path: a sequence of child
+// indexes from the transaction's root down to the node being changed,
+// exactly the indexes ChildrenUnder would require to reach it. This
+// keeps Txn from needing up pointers while still letting it find and
+// clone the spine above an edit.
+type Txn struct {
+	tree *ITree
+	root *INode
+}
+
+// Root returns the transaction's working root, reflecting every edit
+// made so far but not yet visible outside the Txn.
+func (x *Txn) Root() *INode { return x.root }
+
+// Commit atomically swaps the ITree's root for the transaction's
+// working root and returns it. Readers who already took a Snapshot
+// before Commit keep seeing the old root; every Snapshot or Txn taken
+// afterward sees the new one.
+func (x *Txn) Commit() *INode {
+	x.tree.root = x.root
+	return x.root
+}
+
+// copyPath clones the node reached by path from root and every
+// ancestor back up to root, calls edit on the clone at the end of
+// path, and returns the new root. Every child subtree not on path is
+// reused by pointer, untouched.
+func copyPath(root *INode, path []int, edit func(*INode)) *INode {
+	if root == nil {
+		root = &INode{}
+	}
+	clone := *root
+	if len(path) == 0 {
+		edit(&clone)
+		return &clone
+	}
+	idx := path[0]
+	clone.children = append([]*INode(nil), root.children...)
+	clone.children[idx] = copyPath(root.children[idx], path[1:], edit)
+	return &clone
+}
+
+// NewUnder clones the node at path and appends a new leaf or branch
+// under it, returning the new working root.
+func (x *Txn) NewUnder(path []int, t int, v string) *INode {
+	x.root = copyPath(x.root, path, func(n *INode) {
+		n.V = ""
+		n.children = append(n.children, &INode{T: t, V: v})
+	})
+	return x.root
+}
+
+// GraftUnder clones the node at path and adds c as its last child,
+// returning the new working root.
+func (x *Txn) GraftUnder(path []int, c *INode) *INode {
+	x.root = copyPath(x.root, path, func(n *INode) {
+		n.V = ""
+		n.children = append(n.children, c)
+	})
+	return x.root
+}
+
+// GraftLeft clones the parent of path and inserts c immediately to
+// the left of the child at path, returning the new working root. path
+// must be non-empty.
+func (x *Txn) GraftLeft(path []int, c *INode) *INode {
+	parent, idx := path[:len(path)-1], path[len(path)-1]
+	x.root = copyPath(x.root, parent, func(n *INode) {
+		children := make([]*INode, 0, len(n.children)+1)
+		children = append(children, n.children[:idx]...)
+		children = append(children, c)
+		children = append(children, n.children[idx:]...)
+		n.children = children
+	})
+	return x.root
+}
+
+// GraftRight clones the parent of path and inserts c immediately to
+// the right of the child at path, returning the new working root.
+// path must be non-empty.
+func (x *Txn) GraftRight(path []int, c *INode) *INode {
+	parent, idx := path[:len(path)-1], path[len(path)-1]
+	x.root = copyPath(x.root, parent, func(n *INode) {
+		children := make([]*INode, 0, len(n.children)+1)
+		children = append(children, n.children[:idx+1]...)
+		children = append(children, c)
+		children = append(children, n.children[idx+1:]...)
+		n.children = children
+	})
+	return x.root
+}
+
+// Graft clones the parent of path and replaces the child at path with
+// c, returning the new working root. Anything under c replaces
+// anything that was under the node being replaced. path must be
+// non-empty.
+func (x *Txn) Graft(path []int, c *INode) *INode {
+	parent, idx := path[:len(path)-1], path[len(path)-1]
+	x.root = copyPath(x.root, parent, func(n *INode) {
+		children := append([]*INode(nil), n.children...)
+		children[idx] = c
+		n.children = children
+	})
+	return x.root
+}
+
+// Prune clones the parent of path and removes the child at path,
+// returning the new working root. path must be non-empty.
+func (x *Txn) Prune(path []int) *INode {
+	parent, idx := path[:len(path)-1], path[len(path)-1]
+	x.root = copyPath(x.root, parent, func(n *INode) {
+		children := make([]*INode, 0, len(n.children)-1)
+		children = append(children, n.children[:idx]...)
+		children = append(children, n.children[idx+1:]...)
+		n.children = children
+	})
+	return x.root
+}
+
+// Take clones the node at to and the node at from, moves every child
+// of from under to, and returns the new working root. from is left
+// with no children.
+func (x *Txn) Take(to, from []int) *INode {
+	walk := x.root
+	for _, idx := range from {
+		walk = walk.children[idx]
+	}
+	taken := append([]*INode(nil), walk.children...)
+
+	x.root = copyPath(x.root, from, func(n *INode) {
+		n.children = nil
+	})
+	x.root = copyPath(x.root, to, func(n *INode) {
+		n.V = ""
+		n.children = append(append([]*INode(nil), n.children...), taken...)
+	})
+	return x.root
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package tree_test
+
+import (
+	"fmt"
+
+	tree "github.com/rwxrob/structs/node"
+)
+
+func ExampleITree_snapshotSurvivesCommit() {
+	it := tree.NewITree(nil, nil) // root is seeded with type 1
+
+	txn := it.Txn()
+	txn.NewUnder(nil, 2, "hi")
+	txn.Commit()
+
+	snap := it.Snapshot()
+	before, _ := snap.MarshalJSON()
+
+	// further commits must not alter a root already handed out
+	txn2 := it.Txn()
+	txn2.NewUnder([]int{0}, 3, "bye")
+	txn2.Commit()
+
+	after, _ := snap.MarshalJSON()
+
+	fmt.Println(string(before) == string(after))
+	fmt.Println(string(before))
+	// Output:
+	// true
+	// [1,[[2,"hi"]]]
+}
+
+func ExampleNewINode() {
+	n := tree.NewINode(1, "", tree.NewINode(2, "a"), tree.NewINode(2, "b"))
+	b, _ := n.MarshalJSON()
+	fmt.Println(string(b))
+	// Output:
+	// [1,[[2,"a"],[2,"b"]]]
+}
+
+func ExampleTxn_Graft() {
+	it := tree.NewITree(nil, nil)
+	txn := it.Txn()
+	txn.NewUnder(nil, 2, "a")
+	txn.Commit()
+
+	txn2 := it.Txn()
+	txn2.Graft([]int{0}, tree.NewINode(3, "b"))
+	root := txn2.Commit()
+
+	b, _ := root.MarshalJSON()
+	fmt.Println(string(b))
+	// Output:
+	// [1,[[3,"b"]]]
+}
+
+func ExampleTxn_GraftLeft() {
+	it := tree.NewITree(nil, nil)
+	txn := it.Txn()
+	txn.NewUnder(nil, 2, "b")
+	txn.Commit()
+
+	txn2 := it.Txn()
+	txn2.GraftLeft([]int{0}, tree.NewINode(2, "a"))
+	root := txn2.Commit()
+
+	b, _ := root.MarshalJSON()
+	fmt.Println(string(b))
+	// Output:
+	// [1,[[2,"a"],[2,"b"]]]
+}
+
+func ExampleTxn_GraftRight() {
+	it := tree.NewITree(nil, nil)
+	txn := it.Txn()
+	txn.NewUnder(nil, 2, "a")
+	txn.Commit()
+
+	txn2 := it.Txn()
+	txn2.GraftRight([]int{0}, tree.NewINode(2, "b"))
+	root := txn2.Commit()
+
+	b, _ := root.MarshalJSON()
+	fmt.Println(string(b))
+	// Output:
+	// [1,[[2,"a"],[2,"b"]]]
+}
+
+func ExampleTxn_Prune() {
+	it := tree.NewITree(nil, nil)
+	txn := it.Txn()
+	txn.NewUnder(nil, 2, "a")
+	txn.NewUnder(nil, 2, "b")
+	txn.Commit()
+
+	txn2 := it.Txn()
+	txn2.Prune([]int{0})
+	root := txn2.Commit()
+
+	b, _ := root.MarshalJSON()
+	fmt.Println(string(b))
+	// Output:
+	// [1,[[2,"b"]]]
+}
+
+func ExampleTxn_Take() {
+	it := tree.NewITree(nil, nil)
+	txn := it.Txn()
+	txn.NewUnder(nil, 2, "")       // childA at index 0
+	txn.NewUnder(nil, 3, "")       // childB at index 1
+	txn.NewUnder([]int{0}, 9, "x") // grandchild under childA
+	txn.NewUnder([]int{0}, 9, "y") // grandchild under childA
+	txn.Commit()
+
+	txn2 := it.Txn()
+	txn2.Take([]int{1}, []int{0}) // move childA's children under childB
+	root := txn2.Commit()
+
+	b, _ := root.MarshalJSON()
+	fmt.Println(string(b))
+	// Output:
+	// [1,[[2],[3,[[9,"x"],[9,"y"]]]]]
+}
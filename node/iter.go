@@ -0,0 +1,102 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+// Iter walks a Node tree lazily, one Node per Next call, without the
+// recursion or channel dance Visit and VisitAsync need. It is modeled
+// on hashicorp/go-immutable-radix's iterator: an explicit stack of
+// frames, each holding a node's children and how far into them the
+// walk has gotten, pushed on descent and popped once exhausted. That
+// makes it possible to stop early, compose with a plain Go loop, and
+// avoid materializing AllUnder slices for anything but the level
+// currently being walked.
+type Iter struct {
+	root   *Node
+	stack  []iterFrame
+	filter func(*Node) bool
+}
+
+type iterFrame struct {
+	nodes []*Node
+	idx   int
+}
+
+// Iterator returns an Iter that walks n and everything under it,
+// top to bottom and left to right, the same order Visit uses.
+func (n *Node) Iterator() *Iter {
+	return &Iter{root: n, stack: []iterFrame{{nodes: []*Node{n}}}}
+}
+
+// Filter restricts the Iter to Nodes for which keep returns true.
+// Skipped Nodes are still descended into; only their own appearance
+// from Next is suppressed. Returns it for chaining.
+func (it *Iter) Filter(keep func(*Node) bool) *Iter {
+	it.filter = keep
+	return it
+}
+
+// SeekPrefix fast-forwards the Iter so it only visits the subtree
+// reached by descending through children whose T matches types in
+// order, then walks normally from there. It returns it for chaining.
+// If no such path exists the Iter is left exhausted.
+func (it *Iter) SeekPrefix(types ...int) *Iter {
+	if len(types) == 0 {
+		return it
+	}
+	nodes := it.root.AllUnder()
+	var found *Node
+	for _, t := range types {
+		found = nil
+		for _, n := range nodes {
+			if n.T == t {
+				found = n
+				break
+			}
+		}
+		if found == nil {
+			it.stack = nil
+			return it
+		}
+		nodes = found.AllUnder()
+	}
+	it.stack = []iterFrame{{nodes: []*Node{found}}}
+	return it
+}
+
+// SeekPath fast-forwards the Iter to start from every Node matched by
+// path (see Node.Get), then walks normally from there. It returns it
+// for chaining. If path matches nothing the Iter is left exhausted.
+func (it *Iter) SeekPath(path string) *Iter {
+	matches := it.root.Get(path)
+	if len(matches) == 0 {
+		it.stack = nil
+		return it
+	}
+	it.stack = []iterFrame{{nodes: matches}}
+	return it
+}
+
+// Next returns the next Node in the walk, descending into each Node
+// the moment it is returned so children are visited before the rest
+// of their level. The second return is false once the walk and
+// everything under it has been exhausted.
+func (it *Iter) Next() (*Node, bool) {
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		if top.idx >= len(top.nodes) {
+			it.stack = it.stack[:len(it.stack)-1]
+			continue
+		}
+		n := top.nodes[top.idx]
+		top.idx++
+		if children := n.AllUnder(); len(children) > 0 {
+			it.stack = append(it.stack, iterFrame{nodes: children})
+		}
+		if it.filter != nil && !it.filter(n) {
+			continue
+		}
+		return n, true
+	}
+	return nil, false
+}
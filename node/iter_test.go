@@ -0,0 +1,52 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import "fmt"
+
+func ExampleIter_Next() {
+	tr := &Tree{types: map[int]string{1: "Root", 2: "Statement"}}
+	root := &Node{T: 1, tree: tr}
+	root.GraftUnder(&Node{T: 2, V: "x", tree: tr})
+	root.GraftUnder(&Node{T: 2, V: "y", tree: tr})
+
+	it := root.Iterator()
+	for {
+		n, ok := it.Next()
+		if !ok {
+			break
+		}
+		if n.V == "" {
+			fmt.Println(n.T)
+		} else {
+			fmt.Println(n.T, n.V)
+		}
+	}
+	// Output:
+	// 1
+	// 2 x
+	// 2 y
+}
+
+func ExampleIter_SeekPrefix() {
+	tr := &Tree{types: map[int]string{1: "Root", 2: "Statement", 3: "Name"}}
+	root := &Node{T: 1, tree: tr}
+	stmt := &Node{T: 2, tree: tr}
+	root.GraftUnder(stmt)
+	stmt.GraftUnder(&Node{T: 3, V: "x", tree: tr})
+
+	it := root.Iterator().SeekPrefix(2)
+	n, ok := it.Next()
+	fmt.Println(ok, n == stmt)
+
+	n, ok = it.Next()
+	fmt.Println(ok, n.V)
+
+	_, ok = it.Next()
+	fmt.Println(ok)
+	// Output:
+	// true true
+	// true x
+	// false
+}
@@ -4,6 +4,8 @@
 package tree
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"log"
 	"strings"
@@ -48,6 +50,71 @@ type Node struct {
 	last  *Node // last sub
 }
 
+// -------------------------------- tree -------------------------------
+
+// Tree holds a single rooted Node graph along with the type tables
+// every Node on it uses to resolve type names: types maps a Node's
+// integer T to its name for pretty-printing and encoding; typesm is
+// types inverted, for resolving a name back to its T (used by
+// SetType and Get).
+type Tree struct {
+	Root *Node
+
+	types  map[int]string
+	typesm map[string]int
+}
+
+// newTree builds a Tree with its type tables set up but no Root,
+// shared by NewTree, Parse, and NewGrammar, each of which seeds Root
+// differently (or, for Grammar, not at all).
+func newTree(types map[int]string) *Tree {
+	typesm := make(map[string]int, len(types))
+	for i, name := range types {
+		typesm[name] = i
+	}
+	return &Tree{types: types, typesm: typesm}
+}
+
+// NewTree creates an empty Tree whose Nodes resolve their type names
+// against types. Its Root is seeded with type 1, matching the rule
+// that a new Tree always assigns the root Node that type.
+func NewTree(types map[int]string) *Tree {
+	t := newTree(types)
+	t.Root = &Node{T: 1, tree: t}
+	return t
+}
+
+// Seed creates a new, detached Node on this Tree. The first of i, if
+// given, sets the Node's type the same way SetType does (string name
+// or int); the second, if given and a string, sets its initial value.
+func (t *Tree) Seed(i ...any) *Node {
+	n := &Node{tree: t}
+	if len(i) > 0 {
+		if err := n.SetType(i[0]); err != nil {
+			log.Print(err)
+		}
+	}
+	if len(i) > 1 {
+		if v, ok := i[1].(string); ok {
+			n.V = v
+		}
+	}
+	return n
+}
+
+// Parse reads the two-element array form that MarshalJSON and
+// EncodeTo produce from in and returns a new Tree, using types to
+// resolve type names, with its Root set to what was parsed.
+func Parse(in []byte, types map[int]string) (*Tree, error) {
+	t := newTree(types)
+	root, err := decodeNode(bufio.NewReader(bytes.NewReader(in)), t)
+	if err != nil {
+		return nil, err
+	}
+	t.Root = root
+	return t, nil
+}
+
 // ----------------------------- accessors ----------------------------
 
 // Branch returns the current branch this Node is on, or nil.
@@ -419,18 +486,18 @@ type Action func(n *Node) any
 // enclosing some state variable. If the rvals channel is nil it will
 // not be opened.
 func (n *Node) Visit(act Action, rvals chan interface{}) {
-	if rvals == nil {
-		act(n)
-	} else {
-		rvals <- act(n)
-	}
-	if n.first == nil {
-		return
-	}
-	for _, c := range n.AllUnder() {
-		c.Visit(act, rvals)
+	it := n.Iterator()
+	for {
+		c, ok := it.Next()
+		if !ok {
+			return
+		}
+		if rvals == nil {
+			act(c)
+		} else {
+			rvals <- act(c)
+		}
 	}
-	return
 }
 
 // VisitAsync walks a parent Node and all its Children asynchronously by
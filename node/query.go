@@ -0,0 +1,208 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Get navigates the tree rooted at n using a compact, gjson-inspired
+// path expression and returns every Node that matches. A path is
+// a dot-separated list of segments evaluated left to right against
+// n's descendants:
+//
+//   - a type name, e.g. "Assignment", matches children whose type
+//     name (resolved via Tree.typesm) equals it
+//   - an integer, e.g. "0", selects that index from the current
+//     match set
+//   - "*" matches every child of the current match set
+//   - a leading ".." before a segment makes it match anywhere under
+//     the current match set rather than only direct children, e.g.
+//     "Statement..Name"
+//   - a bracketed predicate narrows a name or "*" segment by value,
+//     e.g. `Name[V="x"]` (exact) or `Name[V~="regex"]` (regexp)
+//   - a trailing "#" replaces the match set with a single synthetic
+//     leaf Node holding the match count as its value
+//
+// Get("Statement.Assignment.Name") walks Statement children, then
+// their Assignment children, then their Name children.
+func (n *Node) Get(path string) []*Node {
+	segs := parsePath(path)
+	cur := []*Node{n}
+	for _, seg := range segs {
+		cur = seg.apply(n.tree, cur)
+		if cur == nil {
+			return nil
+		}
+	}
+	return cur
+}
+
+// First returns the first Node matched by path, or nil if none match.
+func (n *Node) First(path string) *Node {
+	m := n.Get(path)
+	if len(m) == 0 {
+		return nil
+	}
+	return m[0]
+}
+
+// Exists reports whether path matches at least one Node.
+func (n *Node) Exists(path string) bool { return len(n.Get(path)) > 0 }
+
+// ------------------------------ segment ------------------------------
+
+type segKind int
+
+const (
+	segName segKind = iota
+	segIndex
+	segWild
+	segCount
+)
+
+type predicate struct {
+	negate bool
+	re     *regexp.Regexp // set when op is ~=
+	val    string         // set when op is =
+}
+
+func (p *predicate) match(v string) bool {
+	var ok bool
+	if p.re != nil {
+		ok = p.re.MatchString(v)
+	} else {
+		ok = v == p.val
+	}
+	return ok
+}
+
+type segment struct {
+	recursive bool
+	kind      segKind
+	name      string
+	index     int
+	pred      *predicate
+}
+
+// apply advances the current match set by this segment, resolving
+// type names against tree.
+func (s segment) apply(tree *Tree, cur []*Node) []*Node {
+	switch s.kind {
+	case segIndex:
+		if s.index < 0 || s.index >= len(cur) {
+			return nil
+		}
+		return []*Node{cur[s.index]}
+	case segCount:
+		return []*Node{{T: 0, V: strconv.Itoa(len(cur))}}
+	}
+
+	want := -1 // segWild matches every type
+	if s.kind == segName {
+		var ok bool
+		want, ok = tree.typesm[s.name]
+		if !ok {
+			return nil
+		}
+	}
+
+	var out []*Node
+	for _, m := range cur {
+		var children []*Node
+		if s.recursive {
+			children = descendants(m)
+		} else {
+			children = m.AllUnder()
+		}
+		for _, c := range children {
+			if want != -1 && c.T != want {
+				continue
+			}
+			if s.pred != nil && !s.pred.match(c.V) {
+				continue
+			}
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// descendants returns every Node under n, at any depth, in the same
+// top-to-bottom left-to-right order Visit uses.
+func descendants(n *Node) []*Node {
+	var out []*Node
+	for _, c := range n.AllUnder() {
+		out = append(out, c)
+		out = append(out, descendants(c)...)
+	}
+	return out
+}
+
+// ------------------------------- parse -------------------------------
+
+func parsePath(path string) []segment {
+	raw := strings.Split(path, ".")
+	var segs []segment
+	recursive := false
+	for _, r := range raw {
+		if r == "" {
+			recursive = true
+			continue
+		}
+		segs = append(segs, parseSegment(r, recursive))
+		recursive = false
+	}
+	return segs
+}
+
+func parseSegment(raw string, recursive bool) segment {
+	s := segment{recursive: recursive}
+
+	if i := strings.IndexByte(raw, '['); i >= 0 && strings.HasSuffix(raw, "]") {
+		s.pred = parsePredicate(raw[i+1 : len(raw)-1])
+		raw = raw[:i]
+	}
+
+	switch {
+	case raw == "#":
+		s.kind = segCount
+	case raw == "*":
+		s.kind = segWild
+	default:
+		if idx, err := strconv.Atoi(raw); err == nil {
+			s.kind = segIndex
+			s.index = idx
+		} else {
+			s.kind = segName
+			s.name = raw
+		}
+	}
+	return s
+}
+
+// parsePredicate parses the inside of a "[...]" clause, one of
+// `V="literal"` or `V~="regex"`. The key is currently always V; it is
+// kept in the syntax so other leaf fields can be supported later
+// without a breaking change.
+func parsePredicate(raw string) *predicate {
+	op := "="
+	if i := strings.Index(raw, "~="); i >= 0 {
+		op = "~="
+		raw = raw[i+2:]
+	} else if i := strings.Index(raw, "="); i >= 0 {
+		raw = raw[i+1:]
+	}
+	raw = strings.Trim(raw, `"`)
+	if op == "~=" {
+		re, err := regexp.Compile(raw)
+		if err != nil {
+			return &predicate{val: raw}
+		}
+		return &predicate{re: re}
+	}
+	return &predicate{val: raw}
+}
@@ -0,0 +1,29 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import "fmt"
+
+func ExampleNode_Get() {
+	types := map[int]string{1: "Program", 2: "Statement", 3: "Assignment", 4: "Name"}
+	typesm := map[string]int{"Program": 1, "Statement": 2, "Assignment": 3, "Name": 4}
+	tr := &Tree{types: types, typesm: typesm}
+
+	root := &Node{T: 1, tree: tr}
+	for _, name := range []string{"x", "y", "z"} {
+		stmt := &Node{T: 2, tree: tr}
+		root.GraftUnder(stmt)
+		assign := &Node{T: 3, tree: tr}
+		stmt.GraftUnder(assign)
+		assign.GraftUnder(&Node{T: 4, V: name, tree: tr})
+	}
+
+	for _, m := range root.Get("Statement.Assignment.Name") {
+		fmt.Println(m.V)
+	}
+	// Output:
+	// x
+	// y
+	// z
+}